@@ -0,0 +1,70 @@
+package errors
+
+import (
+	"context"
+	stderrors "errors"
+	"sync/atomic"
+)
+
+// ContextExtractor pulls request-scoped fields, such as a trace id,
+// out of a context.Context to merge into the log record.
+type ContextExtractor func(ctx context.Context) map[string]any
+
+var contextExtractor atomic.Value // ContextExtractor
+
+// SetContextExtractor configures the function HandleCtx uses to pull
+// request-scoped fields out of a context.Context before logging. Safe
+// to call concurrently with HandleCtx, the same as SetMinSeverity and
+// OnFatal.
+func SetContextExtractor(fn ContextExtractor) {
+	contextExtractor.Store(fn)
+}
+
+// currentContextExtractor returns the configured ContextExtractor, or
+// nil if none has been set.
+func currentContextExtractor() ContextExtractor {
+	fn, _ := contextExtractor.Load().(ContextExtractor)
+	return fn
+}
+
+// HandleCtx is Handle, but merges fields extracted from ctx into the
+// log record and skips logging entirely if ctx is already done and
+// err is context.Canceled. It makes the package usable inside
+// HTTP/gRPC handlers, where every log line needs a trace id and a
+// cancellation storm shouldn't produce a stack trace per goroutine.
+func HandleCtx(ctx context.Context, err error, msg ...any) error {
+	if err == nil {
+		return nil
+	}
+
+	if Err, ok := err.(ExtendedError); ok {
+		return Err.HandleCtx(ctx, msg...)
+	}
+
+	if len(msg) > 0 {
+		if t, ok := msg[0].(string); ok { // first string will be interpreted as error id
+			e := New(err, t, concatMsg(msg[1:]...))
+			return e.HandleCtx(ctx)
+		}
+	}
+	return err
+}
+
+// HandleCtx behaves like Handle, additionally merging any fields
+// extracted from ctx (see SetContextExtractor) into the log record
+// and propagating ctx into the registered Reporters' fan-out.
+func (e *ExtendedError) HandleCtx(ctx context.Context, logmsg ...any) error {
+	if ctx.Err() != nil && stderrors.Is(e.werr, context.Canceled) {
+		e.Handled = true
+		return *e
+	}
+
+	if fn := currentContextExtractor(); fn != nil {
+		e.WithFields(fn(ctx))
+	}
+
+	e.ctx = ctx
+	handle(*e, concatMsg(logmsg...))
+	e.Handled = true
+	return *e
+}
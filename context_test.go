@@ -0,0 +1,58 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestHandleCtxSkipsLoggingOnCanceledContext(t *testing.T) {
+	var buf bytes.Buffer
+	AddOutput("test-ctx", &buf, JSONFormat)
+	defer RemoveOutput("test-ctx")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	e := New(context.Canceled, LogErr, "request aborted")
+	if err := e.HandleCtx(ctx); err == nil {
+		t.Fatal("expected HandleCtx to still return the error")
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("expected no log output for an already-canceled context, got: %s", buf.String())
+	}
+	if !e.Handled {
+		t.Fatal("expected Handled to be set even when logging is skipped")
+	}
+}
+
+func TestHandleCtxLogsWhenNotCanceled(t *testing.T) {
+	var buf bytes.Buffer
+	AddOutput("test-ctx-live", &buf, JSONFormat)
+	defer RemoveOutput("test-ctx-live")
+
+	e := New(nil, LogErr, "still live")
+	_ = e.HandleCtx(context.Background())
+
+	if buf.Len() == 0 {
+		t.Fatal("expected a live context to log normally")
+	}
+}
+
+func TestHandleCtxMergesExtractedFields(t *testing.T) {
+	defer SetContextExtractor(nil)
+	SetContextExtractor(func(ctx context.Context) map[string]any {
+		return map[string]any{"trace_id": "abc123"}
+	})
+
+	var buf bytes.Buffer
+	AddOutput("test-ctx-fields", &buf, JSONFormat)
+	defer RemoveOutput("test-ctx-fields")
+
+	e := New(nil, LogErr, "with trace id")
+	_ = e.HandleCtx(context.Background())
+
+	if !bytes.Contains(buf.Bytes(), []byte(`"trace_id":"abc123"`)) {
+		t.Fatalf("expected extracted field in output, got: %s", buf.String())
+	}
+}
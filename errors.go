@@ -1,9 +1,12 @@
 package errors
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"reflect"
 	"strings"
+	"time"
 
 	pkgerr "github.com/pkg/errors"
 )
@@ -41,13 +44,16 @@ var errlog LogFunc = func(elem ...any) {
 // default function to log fatal errors
 // NOTE:
 // the first arg passed will be used for the log prefix
+// NOTE:
+// this only logs; handle() calls the registered OnFatal hook
+// separately to decide whether to exit, panic, or continue
 var faillog LogFunc = func(elem ...any) {
 	if len(elem) == 0 {
 		return
 	}
 	log.SetPrefix(elem[0].(string))
 	log.SetFlags(log.Ldate | log.Ltime)
-	log.Fatalln(elem...)
+	log.Println(elem...)
 }
 
 // the print function is the default function used to print messages for the user
@@ -55,6 +61,16 @@ var print PrintFunc = func(msg ...any) {
 	fmt.Println(msg...)
 }
 
+// isDefaultLogFn reports whether fn is still one of the package's
+// stock loggers (as opposed to one installed by a caller via Log).
+// handle() uses this to route the unmodified default case through
+// writeDefaultText instead of the log package's global,
+// unsynchronized SetPrefix state.
+func isDefaultLogFn(fn LogFunc) bool {
+	p := reflect.ValueOf(fn).Pointer()
+	return p == reflect.ValueOf(errlog).Pointer() || p == reflect.ValueOf(faillog).Pointer()
+}
+
 // ExtendedError is a type that allows for handling the error
 // and continuing the function execution, returning immediately,
 // or exiting the program. This error defaults to level 1
@@ -68,17 +84,44 @@ type ExtendedError struct {
 	logfn       LogFunc   // function to use for logging
 	printfn     PrintFunc // function to use to print user messages
 	stackFrames int       // number of stack frame to log
-	Handled     bool      // to prevent multiple logging episodes
+	severity    Severity  // how serious the event is, used by SetMinSeverity filtering
+	fields      map[string]any
+	stack       error           // stack captured at the Wrap call site, if any
+	ctx         context.Context // set by HandleCtx, propagated into Reporter fan-out
+	Handled     bool            // to prevent multiple logging episodes
+}
+
+// WithField attaches a single piece of structured context to e. It
+// survives through Handle into every registered output.
+func (e *ExtendedError) WithField(k string, v any) *ExtendedError {
+	if e.fields == nil {
+		e.fields = make(map[string]any)
+	}
+	e.fields[k] = v
+	return e
+}
+
+// WithFields merges fields into the structured context attached to
+// e. It survives through Handle into every registered output.
+func (e *ExtendedError) WithFields(fields map[string]any) *ExtendedError {
+	if e.fields == nil {
+		e.fields = make(map[string]any, len(fields))
+	}
+	for k, v := range fields {
+		e.fields[k] = v
+	}
+	return e
 }
 
 // NewExtendedError returns a useable instance of the type
 // if err is != nil it will be wrapped by werr
 func New(err error, id string, usermsg string) *ExtendedError {
-	e := &ExtendedError{Id: id, usermsg: usermsg, level: 1, stackFrames: 3, logfn: ErrLogger, printfn: ErrPrinter}
+	e := &ExtendedError{Id: id, usermsg: usermsg, level: 1, stackFrames: 3, severity: ERROR, logfn: ErrLogger, printfn: ErrPrinter}
 	if id == Fail || id == Panic {
 		e.logfn = FailLogger
 		e.level = 4
 		e.stackFrames = -1
+		e.severity = FATAL
 	} else {
 		e.Id = LogErr
 	}
@@ -137,13 +180,52 @@ func (e ExtendedError) Unwrap() error {
 	return e.werr
 }
 
-func stackFrames(err error, f ...int) string {
-	type stackTracer interface {
-		StackTrace() pkgerr.StackTrace
+type stackTracer interface {
+	StackTrace() pkgerr.StackTrace
+}
+
+// mergedStackTrace walks err's Unwrap chain and concatenates the
+// StackTrace() of every stackTracer found along the way. An
+// *ExtendedError's own wrap-site stack (set by Wrap, but not part of
+// its Unwrap chain since it isn't the wrapped cause) is spliced in
+// too, so layering several Wrap calls on the same error merges each
+// one's frames instead of losing all but the outermost.
+func mergedStackTrace(err error) pkgerr.StackTrace {
+	var all pkgerr.StackTrace
+	for cur := err; cur != nil; {
+		if st, ok := cur.(stackTracer); ok {
+			all = append(all, st.StackTrace()...)
+		}
+
+		switch v := cur.(type) {
+		case *ExtendedError:
+			if v.stack != nil {
+				if st, ok := v.stack.(stackTracer); ok {
+					all = append(all, st.StackTrace()...)
+				}
+			}
+			cur = v.werr
+		case ExtendedError:
+			if v.stack != nil {
+				if st, ok := v.stack.(stackTracer); ok {
+					all = append(all, st.StackTrace()...)
+				}
+			}
+			cur = v.werr
+		default:
+			u, ok := cur.(interface{ Unwrap() error })
+			if !ok {
+				return all
+			}
+			cur = u.Unwrap()
+		}
 	}
+	return all
+}
 
-	er, ok := err.(stackTracer)
-	if !ok {
+func stackFrames(err error, f ...int) string {
+	st := mergedStackTrace(err)
+	if len(st) == 0 {
 		return ""
 	}
 
@@ -155,7 +237,6 @@ func stackFrames(err error, f ...int) string {
 		}
 	}
 
-	st := er.StackTrace()
 	var ststr string
 	if fr > 0 && fr < len(st) {
 		ststr = fmt.Sprintf("%+v", st[0:fr]) // top f[0] frames
@@ -186,7 +267,14 @@ func concatMsg(msg ...any) string {
 }
 
 func handle(err ExtendedError, logmsg string) {
+	if err.severity < currentMinSeverity() {
+		return
+	}
+
 	e := pkgerr.WithStack(err)
+	if err.stack != nil {
+		e = err.stack // captured at the Wrap call site; prefer it over a fresh trace from here
+	}
 
 	em := err.usermsg
 	if err.werr != nil {
@@ -195,12 +283,49 @@ func handle(err ExtendedError, logmsg string) {
 
 	err.printfn(em)
 
+	if forcedStackPrint {
+		err.stackFrames = -1
+	}
+
 	st := stackFrames(e, err.stackFrames)
+
+	var wrapped string
+	if err.werr != nil {
+		wrapped = err.werr.Error()
+	}
+	var stackLines []string
+	if st != "" {
+		stackLines = strings.Split(st, "\n")
+	}
+	rec := &Record{
+		Ts:       time.Now(),
+		Severity: err.severity,
+		Id:       err.Id,
+		UserMsg:  err.usermsg,
+		Wrapped:  wrapped,
+		Stack:    stackLines,
+		Fields:   err.fields,
+		LogMsg:   logmsg,
+	}
+
 	if err.level > 0 {
-		if err.stackFrames == 0 {
+		if isDefaultLogFn(err.logfn) {
+			// unmodified default: written to os.Stderr by
+			// writeDefaultText instead of mutating log's global
+			// prefix, which isn't safe across concurrent goroutines
+			writeDefaultText(rec)
+		} else if err.stackFrames == 0 {
 			err.logfn(err.Id, fmt.Sprintf("%s\nPrinted for user: %v\n", logmsg, err))
 		} else {
 			err.logfn(err.Id, fmt.Sprintf("%s\nPrinted for user: %+v\n%s ", logmsg, err, st))
 		}
+
+		writeOutputs(rec)
+	}
+
+	report(err.ctx, rec)
+
+	if err.Id == Fail || err.Id == Panic {
+		runFatalHook(err)
 	}
 }
@@ -0,0 +1,65 @@
+package errors
+
+import (
+	"context"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// reportFlushTimeout bounds how long the default ExitProcess action
+// waits for in-flight Reporter fan-out to finish before exiting.
+const reportFlushTimeout = 5 * time.Second
+
+// FatalAction tells handle how to react to a Fail or Panic
+// ExtendedError once its logging and reporting have completed.
+type FatalAction int
+
+const (
+	// ExitProcess waits up to reportFlushTimeout for any in-flight
+	// Reporter fan-out to finish, then calls os.Exit(1). This is the
+	// package's historical behavior, with a bounded flush added so a
+	// crash reported to Rollbar/Sentry/etc. actually ships before the
+	// process dies.
+	ExitProcess FatalAction = iota
+	// FatalPanic panics with the ExtendedError instead of exiting.
+	FatalPanic
+	// ContinueExec returns control to the caller without exiting or
+	// panicking.
+	ContinueExec
+)
+
+type fatalHookFunc func(err ExtendedError) FatalAction
+
+var fatalHook atomic.Value // fatalHookFunc
+
+func init() {
+	fatalHook.Store(fatalHookFunc(func(ExtendedError) FatalAction {
+		return ExitProcess
+	}))
+}
+
+// OnFatal overrides how handle reacts to Fail/Panic errors. Tests and
+// long-lived servers can use it to intercept what would otherwise be
+// an immediate process exit, for example converting it into a
+// graceful shutdown. The default action is ExitProcess, preserving
+// the package's historical behavior.
+func OnFatal(fn func(err ExtendedError) FatalAction) {
+	fatalHook.Store(fatalHookFunc(fn))
+}
+
+// runFatalHook asks the registered hook what to do with a fatal err
+// and carries out its decision.
+func runFatalHook(err ExtendedError) {
+	switch fatalHook.Load().(fatalHookFunc)(err) {
+	case FatalPanic:
+		panic(err)
+	case ContinueExec:
+		return
+	default:
+		ctx, cancel := context.WithTimeout(context.Background(), reportFlushTimeout)
+		_ = Flush(ctx)
+		cancel() // os.Exit below never runs deferred calls, so release explicitly
+		os.Exit(1)
+	}
+}
@@ -13,7 +13,7 @@ func Handle(err error, msg ...any) error {
 	// not an ExtendedError type but will try to convert
 	if len(msg) > 0 {
 		if t, ok := msg[0].(string); ok { // first string will be interpreted as error id
-			e := NewExtendedError(err, t, concatMsg(msg[1:]...))
+			e := New(err, t, concatMsg(msg[1:]...))
 			return e.Handle()
 		}
 	}
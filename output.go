@@ -0,0 +1,152 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Format selects how an output renders a Record.
+type Format int
+
+const (
+	// TextFormat renders the traditional human-readable output.
+	TextFormat Format = iota
+	// JSONFormat renders a structured, single-line JSON record.
+	JSONFormat
+)
+
+// Formatter renders a Record directly into buf, which is drawn from a
+// shared pool, so a registered output never allocates a throwaway
+// []byte per event.
+type Formatter interface {
+	Format(buf *bytes.Buffer, rec *Record)
+}
+
+// Record is the fully materialized shape of a handled error, as seen
+// by Formatters, the registered outputs, and Reporters.
+type Record struct {
+	Ts       time.Time      `json:"ts"`
+	Severity Severity       `json:"severity"`
+	Id       string         `json:"id"`
+	UserMsg  string         `json:"usermsg"`
+	Wrapped  string         `json:"wrapped,omitempty"`
+	Stack    []string       `json:"stack,omitempty"`
+	Fields   map[string]any `json:"fields,omitempty"`
+	LogMsg   string         `json:"logmsg,omitempty"` // the developer-supplied message passed to Handle, if any
+}
+
+type namedOutput struct {
+	name string
+	w    io.Writer
+	fmt  Formatter
+}
+
+var outputs atomic.Value // []namedOutput
+
+func init() {
+	outputs.Store([]namedOutput{})
+}
+
+var defaultWriteMu sync.Mutex
+
+// writeDefaultText renders rec with TextFormatter straight to
+// os.Stderr. Unlike the old errlog/faillog path, this is guarded by
+// its own mutex rather than the log package's unsynchronized global
+// prefix, so it's safe to call from concurrent goroutines.
+func writeDefaultText(rec *Record) {
+	buf := bufPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	TextFormatter{}.Format(buf, rec)
+
+	defaultWriteMu.Lock()
+	os.Stderr.Write(buf.Bytes())
+	defaultWriteMu.Unlock()
+
+	bufPool.Put(buf)
+}
+
+// AddOutput registers w under name, rendered with format. Re-adding
+// an existing name replaces it. The registry is swapped atomically so
+// registration never blocks the hot logging path.
+func AddOutput(name string, w io.Writer, format Format) {
+	var formatter Formatter
+	if format == JSONFormat {
+		formatter = JSONFormatter{}
+	} else {
+		formatter = TextFormatter{}
+	}
+
+	old := outputs.Load().([]namedOutput)
+	next := make([]namedOutput, 0, len(old)+1)
+	for _, o := range old {
+		if o.name != name {
+			next = append(next, o)
+		}
+	}
+	outputs.Store(append(next, namedOutput{name: name, w: w, fmt: formatter}))
+}
+
+// RemoveOutput unregisters the output previously added under name.
+// It is a no-op if name was never registered.
+func RemoveOutput(name string) {
+	old := outputs.Load().([]namedOutput)
+	next := make([]namedOutput, 0, len(old))
+	for _, o := range old {
+		if o.name != name {
+			next = append(next, o)
+		}
+	}
+	outputs.Store(next)
+}
+
+var bufPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// writeOutputs renders rec through every registered output.
+func writeOutputs(rec *Record) {
+	for _, o := range outputs.Load().([]namedOutput) {
+		buf := bufPool.Get().(*bytes.Buffer)
+		buf.Reset()
+		o.fmt.Format(buf, rec)
+		o.w.Write(buf.Bytes())
+		bufPool.Put(buf)
+	}
+}
+
+// TextFormatter renders a Record as the package has always logged:
+// an id-prefixed line with the developer message, user message,
+// wrapped error, and stack.
+type TextFormatter struct{}
+
+// Format implements Formatter.
+func (TextFormatter) Format(buf *bytes.Buffer, rec *Record) {
+	buf.WriteString(rec.Id)
+	if rec.LogMsg != "" {
+		fmt.Fprintf(buf, "%s\n", rec.LogMsg)
+	}
+	fmt.Fprintf(buf, "Printed for user: %s", rec.UserMsg)
+	if rec.Wrapped != "" {
+		fmt.Fprintf(buf, ": %s", rec.Wrapped)
+	}
+	buf.WriteByte('\n')
+	if len(rec.Stack) > 0 {
+		fmt.Fprintf(buf, "%s\n", strings.Join(rec.Stack, "\n"))
+	}
+}
+
+// JSONFormatter renders a Record as a single JSON object followed by
+// a newline.
+type JSONFormatter struct{}
+
+// Format implements Formatter.
+func (JSONFormatter) Format(buf *bytes.Buffer, rec *Record) {
+	_ = json.NewEncoder(buf).Encode(rec) // Encode writes buf directly and appends the trailing newline
+}
@@ -0,0 +1,135 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+)
+
+// Reporter ships a fully materialized error off to a remote sink, such
+// as Rollbar, Sentry, or an internal HTTP endpoint. Report is called
+// once per handled error, after local logging has already happened.
+// rec carries every exported field (Ts, Id, UserMsg, Severity, Wrapped,
+// Stack, Fields, LogMsg) so a Reporter implemented outside this
+// package has the same access to the event as the built-in
+// HTTPJSONReporter.
+type Reporter interface {
+	Report(rec *Record) error
+}
+
+var (
+	reportersMu sync.RWMutex
+	reporters   []Reporter
+	reportWG    sync.WaitGroup
+)
+
+// RegisterReporter adds r to the set of reporters notified by Handle.
+func RegisterReporter(r Reporter) {
+	reportersMu.Lock()
+	defer reportersMu.Unlock()
+	reporters = append(reporters, r)
+}
+
+// UnregisterReporter removes r from the set of active reporters.
+// It is a no-op if r was never registered.
+func UnregisterReporter(r Reporter) {
+	reportersMu.Lock()
+	defer reportersMu.Unlock()
+	for i, reg := range reporters {
+		if reg == r {
+			reporters = append(reporters[:i], reporters[i+1:]...)
+			return
+		}
+	}
+}
+
+// report fans rec out to every registered Reporter concurrently.
+// Reporter errors are swallowed so a single bad backend can't break
+// the caller. If ctx is non-nil (set by HandleCtx), it's honored:
+// fan-out stops early once the context is done.
+func report(ctx context.Context, rec *Record) {
+	reportersMu.RLock()
+	rs := make([]Reporter, len(reporters))
+	copy(rs, reporters)
+	reportersMu.RUnlock()
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	for _, r := range rs {
+		if ctx.Err() != nil {
+			return
+		}
+		reportWG.Add(1)
+		go func(r Reporter) {
+			defer reportWG.Done()
+			done := make(chan struct{})
+			go func() {
+				_ = r.Report(rec)
+				close(done)
+			}()
+			select {
+			case <-done:
+			case <-ctx.Done():
+			}
+		}(r)
+	}
+}
+
+// Flush blocks until all in-flight reporter calls complete, or ctx is
+// done first. Call it at shutdown so async reporters get a chance to
+// drain their queues.
+func Flush(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		reportWG.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// HTTPJSONReporter is a reference Reporter that POSTs each reported
+// error as JSON to a configured endpoint.
+type HTTPJSONReporter struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPJSONReporter returns a Reporter that POSTs a JSON payload for
+// every reported error to url using client. If client is nil,
+// http.DefaultClient is used.
+func NewHTTPJSONReporter(url string, client *http.Client) *HTTPJSONReporter {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &HTTPJSONReporter{url: url, client: client}
+}
+
+// Report implements Reporter.
+func (h *HTTPJSONReporter) Report(rec *Record) error {
+	body, jerr := json.Marshal(rec)
+	if jerr != nil {
+		return jerr
+	}
+
+	req, rerr := http.NewRequest(http.MethodPost, h.url, bytes.NewReader(body))
+	if rerr != nil {
+		return rerr
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, derr := h.client.Do(req)
+	if derr != nil {
+		return derr
+	}
+	return resp.Body.Close()
+}
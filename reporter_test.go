@@ -0,0 +1,97 @@
+package errors
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+type countingReporter struct {
+	mu    sync.Mutex
+	calls int
+	last  *Record
+}
+
+func (c *countingReporter) Report(rec *Record) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls++
+	c.last = rec
+	return nil
+}
+
+func (c *countingReporter) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls
+}
+
+func TestReportFansOutToAllReporters(t *testing.T) {
+	r1, r2 := &countingReporter{}, &countingReporter{}
+	RegisterReporter(r1)
+	RegisterReporter(r2)
+	defer UnregisterReporter(r1)
+	defer UnregisterReporter(r2)
+
+	report(nil, &Record{Id: LogErr, UserMsg: "boom"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := Flush(ctx); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	if r1.count() != 1 || r2.count() != 1 {
+		t.Fatalf("expected both reporters to be called once, got %d and %d", r1.count(), r2.count())
+	}
+	if r1.last.UserMsg != "boom" {
+		t.Fatalf("unexpected record delivered: %+v", r1.last)
+	}
+}
+
+func TestReportSwallowsReporterErrors(t *testing.T) {
+	// a reporter returning an error must not affect another reporter or the caller
+	ok := &countingReporter{}
+	bad := fakeErrReporter{}
+	RegisterReporter(bad)
+	RegisterReporter(ok)
+	defer UnregisterReporter(bad)
+	defer UnregisterReporter(ok)
+
+	report(nil, &Record{Id: LogErr, UserMsg: "still delivered"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := Flush(ctx); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if ok.count() != 1 {
+		t.Fatalf("expected the healthy reporter to still be called once, got %d", ok.count())
+	}
+}
+
+type fakeErrReporter struct{}
+
+func (fakeErrReporter) Report(*Record) error {
+	return context.DeadlineExceeded
+}
+
+func TestReportStopsFanOutOnCanceledContext(t *testing.T) {
+	r := &countingReporter{}
+	RegisterReporter(r)
+	defer UnregisterReporter(r)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel() // already canceled before report() runs
+
+	report(ctx, &Record{Id: LogErr, UserMsg: "should be skipped"})
+
+	flushCtx, flushCancel := context.WithTimeout(context.Background(), time.Second)
+	defer flushCancel()
+	_ = Flush(flushCtx)
+
+	if r.count() != 0 {
+		t.Fatalf("expected fan-out to be skipped once ctx is canceled, got %d calls", r.count())
+	}
+}
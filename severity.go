@@ -0,0 +1,56 @@
+package errors
+
+import (
+	"os"
+	"sync/atomic"
+)
+
+// Severity classifies how serious an error event is, modeled after
+// the levels used by golog and similar structured loggers. The
+// historical string ids (LogErr, Panic, Fail) remain valid for source
+// compatibility; New maps them onto a Severity automatically.
+type Severity int
+
+const (
+	TRACE Severity = 100
+	DEBUG Severity = 200
+	INFO  Severity = 300
+	WARN  Severity = 400
+	ERROR Severity = 500
+	FATAL Severity = 600
+)
+
+var minSeverity atomic.Value // Severity
+
+// forcedStackPrint is set at init from PRINT_STACK and forces handle
+// to log the full stack regardless of an error's own Stack setting.
+var forcedStackPrint bool
+
+func init() {
+	minSeverity.Store(INFO)
+
+	if os.Getenv("TRACE") == "true" {
+		minSeverity.Store(TRACE)
+	}
+	if os.Getenv("PRINT_STACK") == "true" {
+		forcedStackPrint = true
+	}
+}
+
+// SetMinSeverity sets the package-wide floor below which errors are
+// no longer logged or reported. The default is INFO; set TRACE=true
+// in the environment to drop the floor and see everything.
+func SetMinSeverity(s Severity) {
+	minSeverity.Store(s)
+}
+
+func currentMinSeverity() Severity {
+	return minSeverity.Load().(Severity)
+}
+
+// Severity sets the severity of e, overriding the default that New
+// derived from its Id.
+func (e *ExtendedError) Severity(s Severity) *ExtendedError {
+	e.severity = s
+	return e
+}
@@ -0,0 +1,42 @@
+package errors
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSetMinSeverityFiltersLowerSeverity(t *testing.T) {
+	defer SetMinSeverity(currentMinSeverity()) // restore
+
+	var buf bytes.Buffer
+	AddOutput("test-severity", &buf, JSONFormat)
+	defer RemoveOutput("test-severity")
+
+	SetMinSeverity(ERROR)
+
+	quiet := New(nil, LogErr, "should be filtered").Severity(WARN)
+	quiet.Handle()
+	if buf.Len() != 0 {
+		t.Fatalf("expected WARN event below the ERROR floor to be filtered, got: %s", buf.String())
+	}
+
+	loud := New(nil, LogErr, "should pass").Severity(ERROR)
+	loud.Handle()
+	if buf.Len() == 0 {
+		t.Fatal("expected ERROR event at the floor to be logged")
+	}
+}
+
+func TestSetMinSeverityDefaultPassesError(t *testing.T) {
+	defer SetMinSeverity(currentMinSeverity()) // restore
+	SetMinSeverity(INFO)
+
+	var buf bytes.Buffer
+	AddOutput("test-severity-default", &buf, JSONFormat)
+	defer RemoveOutput("test-severity-default")
+
+	New(nil, LogErr, "default severity is ERROR").Handle()
+	if buf.Len() == 0 {
+		t.Fatal("expected a plain New() error (severity ERROR) to pass the INFO floor")
+	}
+}
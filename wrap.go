@@ -0,0 +1,65 @@
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+
+	pkgerr "github.com/pkg/errors"
+)
+
+// Wrap annotates err with msg and returns an *ExtendedError carrying
+// it as the wrapped cause. Unlike Handle, the stack is captured here
+// at the Wrap call site rather than deferred to Handle, so layering
+// several Wrap calls on the same error records where each one was
+// added.
+func Wrap(err error, msg string) *ExtendedError {
+	if err == nil {
+		return nil
+	}
+	e := New(err, LogErr, msg)
+	e.stack = pkgerr.WithStack(err)
+	return e
+}
+
+// Wrapf is Wrap with a fmt.Sprintf-formatted message.
+func Wrapf(err error, format string, args ...any) *ExtendedError {
+	if err == nil {
+		return nil
+	}
+	return Wrap(err, fmt.Sprintf(format, args...))
+}
+
+// Cause walks the Unwrap() chain of err down to its root cause.
+func Cause(err error) error {
+	for err != nil {
+		u, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			break
+		}
+		next := u.Unwrap()
+		if next == nil {
+			break
+		}
+		err = next
+	}
+	return err
+}
+
+// Is delegates to stdlib errors.Is over e's wrapped chain, so e
+// composes with errors.Is. Id only ever takes one of a handful of
+// values (LogErr/Panic/Fail), so it cannot be used to tell unrelated
+// errors apart and is deliberately not consulted here.
+func (e ExtendedError) Is(target error) bool {
+	return stderrors.Is(e.werr, target)
+}
+
+// As delegates to stdlib errors.As over the wrapped chain, so e
+// composes with errors.As. A target of type *ExtendedError is set to
+// e itself rather than to a wrapped error.
+func (e ExtendedError) As(target any) bool {
+	if t, ok := target.(*ExtendedError); ok {
+		*t = e
+		return true
+	}
+	return stderrors.As(e.werr, target)
+}